@@ -0,0 +1,279 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"k8s.io/klog/v2"
+)
+
+// CheckKeyExist check whether the map, i.e. disinfo contains all the keys, i.e. keys
+func CheckKeyExist(keys []string, disinfo map[string]interface{}) error {
+	for _, v := range keys {
+		_, exist := disinfo[v]
+		if !exist {
+			return errors.New("key not found")
+		}
+	}
+	return nil
+}
+
+// CheckClientToken check whether the client token is received or not
+func CheckClientToken(token MQTT.Token) (bool, error) {
+	if token.Wait() && token.Error() != nil {
+		return false, token.Error()
+	}
+	return true, nil
+}
+
+// PathExist check whether the path exists or not
+func PathExist(path string) bool {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true
+	}
+	return !os.IsNotExist(err)
+}
+
+// TLSConfig describes the TLS options used when dialing the mqtt broker. A zero value
+// TLSConfig enables plain, verified TLS; set InsecureSkipVerify to opt out of verification
+// instead of leaving CAFile/CertFile/KeyFile empty.
+type TLSConfig struct {
+	// CAFile is the path to the CA certificate used to verify the broker's server certificate.
+	// When empty, the host's root CA set is used.
+	CAFile string
+	// CertFile and KeyFile, when both set, are loaded as the client certificate/key pair and
+	// enable mTLS: the broker can then require and verify the client certificate.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the server name used to verify the broker's certificate.
+	ServerName string
+	// MinVersion is the minimum TLS version accepted, e.g. tls.VersionTLS12. Defaults to
+	// tls.VersionTLS12 when zero.
+	MinVersion uint16
+	// InsecureSkipVerify disables verification of the broker's certificate chain and host name.
+	InsecureSkipVerify bool
+}
+
+// buildTLSConfig turns a TLSConfig into a *tls.Config, loading the CA and client cert/key
+// from disk as needed. A nil TLSConfig preserves the historical insecure behavior.
+func buildTLSConfig(cfg *TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return &tls.Config{InsecureSkipVerify: true, ClientAuth: tls.NoClientCert}, nil
+	}
+
+	minVersion := cfg.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		MinVersion:         minVersion,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		ClientAuth:         tls.NoClientCert,
+	}
+
+	if cfg.CAFile != "" {
+		caCert, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %v", cfg.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" && cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client key pair: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		// ClientAuth is only consulted on the server side of a handshake; setting it here
+		// marks this config as carrying a client certificate for mTLS.
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// HubClientInit create mqtt client config, defaulting to the historical insecure TLS behavior.
+func HubClientInit(server, clientID, username, password string) *MQTT.ClientOptions {
+	opts, err := HubClientInitWithTLS(server, clientID, username, password, nil)
+	if err != nil {
+		// buildTLSConfig never errors for a nil TLSConfig.
+		klog.Fatalf("failed to init mqtt client options: %v", err)
+	}
+	return opts
+}
+
+// HubClientInitWithTLS create mqtt client config with the given TLS options. Passing a nil
+// tlsConfig keeps the historical insecure, no-client-cert behavior; otherwise it builds a
+// *tls.Config loading the CA and, when both CertFile and KeyFile are set, the client
+// certificate used for mTLS.
+func HubClientInitWithTLS(server, clientID, username, password string, tlsConfig *TLSConfig) (*MQTT.ClientOptions, error) {
+	return HubClientInitWithBrokers([]string{server}, clientID, username, password, tlsConfig, nil, nil)
+}
+
+// HubClientInitWithBrokers creates mqtt client options for a pool of broker URLs, populating
+// ClientOptions.Servers so paho round-robins across the pool on connect and on its own internal
+// auto-reconnect. onConnect and onConnectionLost, when non-nil, are registered on the options so
+// paho invokes them after every successful connect (including reconnects) and whenever the
+// connection drops, letting callers such as eventbus or devicetwin re-subscribe atomically.
+func HubClientInitWithBrokers(servers []string, clientID, username, password string, tlsConfig *TLSConfig, onConnect MQTT.OnConnectHandler, onConnectionLost MQTT.ConnectionLostHandler) (*MQTT.ClientOptions, error) {
+	if len(servers) == 0 {
+		return nil, errors.New("at least one broker server is required")
+	}
+
+	tc, err := buildTLSConfig(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := MQTT.NewClientOptions().SetClientID(clientID).SetCleanSession(true)
+	for _, server := range servers {
+		opts.AddBroker(server)
+	}
+	if username != "" {
+		opts.SetUsername(username)
+		if password != "" {
+			opts.SetPassword(password)
+		}
+	}
+	opts.SetTLSConfig(tc)
+	if onConnect != nil {
+		opts.SetOnConnectHandler(onConnect)
+	}
+	if onConnectionLost != nil {
+		opts.SetConnectionLostHandler(onConnectionLost)
+	}
+	return opts, nil
+}
+
+// PublishOptions describes the QoS and retain flag used for an MQTT publish.
+type PublishOptions struct {
+	// QoS is the MQTT quality of service level: 0 (at most once), 1 (at least once), or
+	// 2 (exactly once).
+	QoS uint8
+	// Retain marks the published message as the broker's last known value for the topic.
+	Retain bool
+}
+
+// SubscribeOptions describes the QoS used for an MQTT subscribe.
+type SubscribeOptions struct {
+	// QoS is the MQTT quality of service level requested for the subscription.
+	QoS uint8
+}
+
+// validateQoS returns an error unless qos is a valid MQTT QoS level (0, 1, or 2).
+func validateQoS(qos uint8) error {
+	if qos > 2 {
+		return fmt.Errorf("invalid QoS %d: must be 0, 1, or 2", qos)
+	}
+	return nil
+}
+
+// Publish validates opts.QoS and publishes payload to topic with the given QoS and retain flag.
+func Publish(client MQTT.Client, topic string, opts PublishOptions, payload []byte) error {
+	if err := validateQoS(opts.QoS); err != nil {
+		return err
+	}
+	token := client.Publish(topic, opts.QoS, opts.Retain, payload)
+	if ok, err := CheckClientToken(token); !ok {
+		return err
+	}
+	return nil
+}
+
+// Subscribe validates opts.QoS and subscribes to topic, invoking callback for each message
+// received at the given QoS.
+func Subscribe(client MQTT.Client, topic string, opts SubscribeOptions, callback MQTT.MessageHandler) error {
+	if err := validateQoS(opts.QoS); err != nil {
+		return err
+	}
+	token := client.Subscribe(topic, opts.QoS, callback)
+	if ok, err := CheckClientToken(token); !ok {
+		return err
+	}
+	return nil
+}
+
+// ReconnectBackoff configures the exponential backoff with jitter LoopConnect applies between
+// connect attempts. A zero value defaults to InitialBackoff=500ms and MaxBackoff=30s.
+type ReconnectBackoff struct {
+	// InitialBackoff is the upper bound of the jittered delay before the second connect
+	// attempt, which waits between InitialBackoff/2 and InitialBackoff (the first attempt is
+	// always immediate).
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay; it is reset back to InitialBackoff on a successful connect.
+	MaxBackoff time.Duration
+}
+
+// LoopConnect connects client to the mqtt server(s) it was configured with, retrying with
+// exponential backoff and jitter (doubling from backoff.InitialBackoff up to
+// backoff.MaxBackoff, reset on success) until it connects or ctx is cancelled. It returns
+// ctx.Err() if ctx is done before a successful connect.
+func LoopConnect(ctx context.Context, clientID string, client MQTT.Client, backoff ReconnectBackoff) error {
+	initialBackoff := backoff.InitialBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = 500 * time.Millisecond
+	}
+	maxBackoff := backoff.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	sub := clientID
+	delay := initialBackoff
+	for {
+		klog.Infof("start connect to mqtt server with client id: %s", sub)
+		token := client.Connect()
+		select {
+		case <-token.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		klog.Infof("client %s isconnected: %v", sub, client.IsConnected())
+		if err := token.Error(); err != nil {
+			klog.Errorf("client %s failed to connect with err: %v", sub, err)
+		} else {
+			return nil
+		}
+
+		jittered := delay/2 + time.Duration(rand.Int63n(int64(delay/2+1)))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jittered):
+		}
+		delay *= 2
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+	}
+}
@@ -14,13 +14,27 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package util
+// Package util_test is an external test package (rather than an internal "package util" test)
+// because it depends on eventconfig, which itself imports util; an internal test file importing
+// eventconfig would create an import cycle.
+package util_test
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -30,6 +44,7 @@ import (
 	"k8s.io/klog/v2"
 
 	eventconfig "github.com/kubeedge/kubeedge/edge/pkg/eventbus/config"
+	util "github.com/kubeedge/kubeedge/edge/pkg/eventbus/common/util"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha1"
 )
 
@@ -64,7 +79,7 @@ func TestCheckKeyExist(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := CheckKeyExist(tt.keys, tt.disinfo)
+			err := util.CheckKeyExist(tt.keys, tt.disinfo)
 			if !reflect.DeepEqual(tt.expectedError, err) {
 				t.Errorf("Expected error contain %s, but error is %v", tt.expectedError, err)
 			}
@@ -86,13 +101,13 @@ func TestCheckClientToken(t *testing.T) {
 		},
 		{
 			name:          "TestCheckClientToken: Client token created with error",
-			token:         MQTT.NewClient(HubClientInit("tcp://127.0.0:8000", "12345", "", "")).Connect(),
+			token:         MQTT.NewClient(util.HubClientInit("tcp://127.0.0:8000", "12345", "", "")).Connect(),
 			expectedError: "Network Error",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rs, err := CheckClientToken(tt.token)
+			rs, err := util.CheckClientToken(tt.token)
 			fmt.Printf("rs  =  %v", rs)
 			if !strings.Contains(err.Error(), tt.expectedError) {
 				t.Errorf("Expected error contain %s, but error is %v", tt.expectedError, err)
@@ -121,7 +136,7 @@ func TestPathExist(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := PathExist(tt.path); !reflect.DeepEqual(got, tt.want) {
+			if got := util.PathExist(tt.path); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("common.TestPathExist() got = %v, want =  %v", got, tt.want)
 			}
 		})
@@ -175,7 +190,7 @@ func TestHubClientInit(t *testing.T) {
 			tt.want.Username = tt.username
 			tt.want.Password = tt.password
 			tt.want.TLSConfig = &tls.Config{InsecureSkipVerify: true, ClientAuth: tls.NoClientCert}
-			got := HubClientInit(tt.server, tt.clientID, tt.username, tt.password)
+			got := util.HubClientInit(tt.server, tt.clientID, tt.username, tt.password)
 			if !reflect.DeepEqual(tt.want.Servers, got.Servers) || tt.want.ClientID != got.ClientID || tt.want.CleanSession != got.CleanSession ||
 				tt.want.Username != got.Username || tt.want.Password != got.Password || !reflect.DeepEqual(tt.want.TLSConfig, got.TLSConfig) {
 				t.Errorf("expected %#v, but got %#v", tt.want, got)
@@ -202,14 +217,16 @@ func TestLoopConnect(t *testing.T) {
 		{
 			name:          "TestLoopConnect: Connection error",
 			clientID:      "12345",
-			clientOptions: HubClientInit("tcp://127.0.0.1:1882", "12345", "test_user", "123456789"),
+			clientOptions: util.HubClientInit("tcp://127.0.0.1:1882", "12345", "test_user", "123456789"),
 			connect:       false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.client = MQTT.NewClient(tt.clientOptions)
-			go LoopConnect(tt.clientID, tt.client)
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			go util.LoopConnect(ctx, tt.clientID, tt.client, util.ReconnectBackoff{InitialBackoff: 2 * time.Millisecond, MaxBackoff: 5 * time.Millisecond})
 			time.Sleep(5 * time.Millisecond)
 			if !tt.client.IsConnected() {
 				if len(tt.clientOptions.Servers) != 0 {
@@ -222,3 +239,336 @@ func TestLoopConnect(t *testing.T) {
 		})
 	}
 }
+
+// newTestCertPEM generates a throwaway self-signed certificate/key pair for TLS tests.
+func newTestCertPEM(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM
+}
+
+func writeTestFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+// TestHubClientInitWithTLS checks HubClientInitWithTLS builds the expected *tls.Config for
+// a verified CA, mTLS, and a mismatched client cert/key pair.
+func TestHubClientInitWithTLS(t *testing.T) {
+	dir := t.TempDir()
+	caCertPEM, _ := newTestCertPEM(t, "test-ca")
+	caFile := writeTestFile(t, dir, "ca.pem", caCertPEM)
+
+	clientCertPEM, clientKeyPEM := newTestCertPEM(t, "test-client")
+	certFile := writeTestFile(t, dir, "client.pem", clientCertPEM)
+	keyFile := writeTestFile(t, dir, "client-key.pem", clientKeyPEM)
+
+	_, otherKeyPEM := newTestCertPEM(t, "other-client")
+	badKeyFile := writeTestFile(t, dir, "other-client-key.pem", otherKeyPEM)
+
+	tests := []struct {
+		name           string
+		tlsConfig      *util.TLSConfig
+		wantErr        bool
+		wantRootCA     bool
+		wantClientAuth tls.ClientAuthType
+	}{
+		{
+			name:           "verified CA",
+			tlsConfig:      &util.TLSConfig{CAFile: caFile},
+			wantRootCA:     true,
+			wantClientAuth: tls.NoClientCert,
+		},
+		{
+			name:           "mTLS with matching client cert/key",
+			tlsConfig:      &util.TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+			wantRootCA:     true,
+			wantClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:      "bad cert/key pair fails to load",
+			tlsConfig: &util.TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: badKeyFile},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := util.HubClientInitWithTLS("tcp://127.0.0.1:1883", "12345", "", "", tt.tlsConfig)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := opts.TLSConfig
+			if tt.wantRootCA && got.RootCAs == nil {
+				t.Errorf("expected RootCAs to be populated, got nil")
+			}
+			if got.ClientAuth != tt.wantClientAuth {
+				t.Errorf("expected ClientAuth %v, got %v", tt.wantClientAuth, got.ClientAuth)
+			}
+		})
+	}
+}
+
+// TestHubClientInitWithTLSDefault checks a nil TLSConfig preserves the historical insecure behavior.
+func TestHubClientInitWithTLSDefault(t *testing.T) {
+	opts, err := util.HubClientInitWithTLS("tcp://127.0.0.1:1883", "12345", "", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &tls.Config{InsecureSkipVerify: true, ClientAuth: tls.NoClientCert}
+	if !reflect.DeepEqual(want, opts.TLSConfig) {
+		t.Errorf("expected %#v, but got %#v", want, opts.TLSConfig)
+	}
+}
+
+// fakeToken is an MQTT.Token that is always already resolved without error.
+type fakeToken struct{}
+
+func (*fakeToken) Wait() bool                     { return true }
+func (*fakeToken) WaitTimeout(time.Duration) bool { return true }
+func (*fakeToken) Done() <-chan struct{}          { ch := make(chan struct{}); close(ch); return ch }
+func (*fakeToken) Error() error                   { return nil }
+
+// fakePublishCall records the arguments of a single mock broker Publish call.
+type fakePublishCall struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  interface{}
+}
+
+// mockBrokerClient is a mock MQTT.Client that records Publish calls and accepts Subscribe
+// calls without recording them, instead of talking to a real broker.
+type mockBrokerClient struct {
+	MQTT.Client
+	published []fakePublishCall
+}
+
+func (m *mockBrokerClient) Publish(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+	m.published = append(m.published, fakePublishCall{topic, qos, retained, payload})
+	return &fakeToken{}
+}
+
+func (m *mockBrokerClient) Subscribe(topic string, qos byte, callback MQTT.MessageHandler) MQTT.Token {
+	return &fakeToken{}
+}
+
+// TestPublish checks Publish validates QoS and forwards topic/QoS/retain to the broker client.
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    util.PublishOptions
+		wantErr bool
+	}{
+		{
+			name: "TestPublish: QoS 0, not retained",
+			opts: util.PublishOptions{QoS: 0, Retain: false},
+		},
+		{
+			name: "TestPublish: QoS 1, retained",
+			opts: util.PublishOptions{QoS: 1, Retain: true},
+		},
+		{
+			name: "TestPublish: QoS 2, not retained",
+			opts: util.PublishOptions{QoS: 2, Retain: false},
+		},
+		{
+			name:    "TestPublish: invalid QoS",
+			opts:    util.PublishOptions{QoS: 3},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockBrokerClient{}
+			err := util.Publish(client, "test/topic", tt.opts, []byte("payload"))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				if len(client.published) != 0 {
+					t.Errorf("expected no publish to reach the broker, got %v", client.published)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(client.published) != 1 {
+				t.Fatalf("expected 1 publish, got %d", len(client.published))
+			}
+			got := client.published[0]
+			if got.topic != "test/topic" || got.qos != tt.opts.QoS || got.retained != tt.opts.Retain {
+				t.Errorf("expected topic=test/topic qos=%d retained=%v, got %+v", tt.opts.QoS, tt.opts.Retain, got)
+			}
+		})
+	}
+}
+
+// TestSubscribe checks Subscribe validates QoS before forwarding to the broker client.
+func TestSubscribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    util.SubscribeOptions
+		wantErr bool
+	}{
+		{name: "TestSubscribe: QoS 1", opts: util.SubscribeOptions{QoS: 1}},
+		{name: "TestSubscribe: invalid QoS", opts: util.SubscribeOptions{QoS: 5}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockBrokerClient{}
+			err := util.Subscribe(client, "test/topic", tt.opts, nil)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected an error, got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// startFakeMQTTBroker listens on an ephemeral localhost port and replies to every connection's
+// MQTT CONNECT packet with a successful CONNACK, just enough for paho to consider itself
+// connected. It returns the broker's tcp:// URL and a func to stop listening.
+func startFakeMQTTBroker(t *testing.T) (addr string, stop func()) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				buf := make([]byte, 256)
+				if _, err := c.Read(buf); err != nil {
+					return
+				}
+				// CONNACK: fixed header (type 0x20, remaining length 2), session present 0, return code 0 (accepted).
+				if _, err := c.Write([]byte{0x20, 0x02, 0x00, 0x00}); err != nil {
+					return
+				}
+				io.Copy(io.Discard, c)
+			}(conn)
+		}
+	}()
+	return "tcp://" + ln.Addr().String(), func() { ln.Close() }
+}
+
+// TestLoopConnectFailover checks that a client configured with a pool of brokers connects via
+// the second broker when the first is unreachable.
+func TestLoopConnectFailover(t *testing.T) {
+	addr1, stop1 := startFakeMQTTBroker(t)
+	addr2, stop2 := startFakeMQTTBroker(t)
+	defer stop2()
+	stop1()
+
+	opts, err := util.HubClientInitWithBrokers([]string{addr1, addr2}, "failover-client", "", "", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := MQTT.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	backoff := util.ReconnectBackoff{InitialBackoff: 50 * time.Millisecond, MaxBackoff: 200 * time.Millisecond}
+	if err := util.LoopConnect(ctx, "failover-client", client, backoff); err != nil {
+		t.Fatalf("expected LoopConnect to fail over to the second broker, got: %v", err)
+	}
+	if !client.IsConnected() {
+		t.Errorf("expected client to be connected after failover")
+	}
+}
+
+// TestLoopConnectContextCancellation checks LoopConnect returns ctx.Err() once ctx is done
+// instead of retrying forever.
+func TestLoopConnectContextCancellation(t *testing.T) {
+	client := MQTT.NewClient(util.HubClientInit("tcp://127.0.0.1:1", "cancel-client", "", ""))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := util.LoopConnect(ctx, "cancel-client", client, util.ReconnectBackoff{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// startBlackholeListener accepts connections but never writes a CONNACK, simulating a connect
+// attempt that hangs instead of failing immediately. It returns the listener's tcp:// URL and a
+// func to stop listening.
+func startBlackholeListener(t *testing.T) (addr string, stop func()) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				io.Copy(io.Discard, c)
+			}(conn)
+		}
+	}()
+	return "tcp://" + ln.Addr().String(), func() { ln.Close() }
+}
+
+// TestLoopConnectCancelsWhileConnectHangs checks that ctx cancellation is honored promptly
+// even while a single connect attempt is still in flight, rather than only being checked
+// between connect attempts.
+func TestLoopConnectCancelsWhileConnectHangs(t *testing.T) {
+	addr, stop := startBlackholeListener(t)
+	defer stop()
+
+	client := MQTT.NewClient(util.HubClientInit(addr, "hang-client", "", ""))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := util.LoopConnect(ctx, "hang-client", client, util.ReconnectBackoff{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("expected LoopConnect to return promptly once ctx was done, took %v", elapsed)
+	}
+}
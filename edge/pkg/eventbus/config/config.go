@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/common/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha1"
+)
+
+// tlsVersions maps the EventBusTLS.MinVersion config strings to the tls.VersionTLSxx constants.
+var tlsVersions = map[string]uint16{
+	"TLS1.0": tls.VersionTLS10,
+	"TLS1.1": tls.VersionTLS11,
+	"TLS1.2": tls.VersionTLS12,
+	"TLS1.3": tls.VersionTLS13,
+}
+
+var Config Configure
+var once sync.Once
+
+// Configure holds the resolved eventbus module config plus the node name it runs on.
+type Configure struct {
+	v1alpha1.EventBus
+	NodeName string
+}
+
+// InitConfigure initializes the eventbus module's global Config, it only takes effect on the first call.
+func InitConfigure(eventBus *v1alpha1.EventBus, nodeName string) {
+	once.Do(func() {
+		Config = Configure{
+			EventBus: *eventBus,
+			NodeName: nodeName,
+		}
+	})
+}
+
+// Get returns the eventbus module's global Config.
+func Get() *Configure {
+	return &Config
+}
+
+// TLSConfig converts the module's EventBusTLS config into a *util.TLSConfig suitable for
+// util.HubClientInitWithTLS/HubClientInitWithBrokers. It returns (nil, nil) when TLS is unset
+// or disabled, preserving the historical insecure connection for backward compatibility.
+func (c *Configure) TLSConfig() (*util.TLSConfig, error) {
+	if c.TLS == nil || !c.TLS.Enable {
+		return nil, nil
+	}
+
+	tlsConfig := &util.TLSConfig{
+		CAFile:             c.TLS.CAFile,
+		CertFile:           c.TLS.CertFile,
+		KeyFile:            c.TLS.KeyFile,
+		ServerName:         c.TLS.ServerName,
+		InsecureSkipVerify: c.TLS.InsecureSkipVerify,
+	}
+
+	if c.TLS.MinVersion != "" {
+		version, ok := tlsVersions[c.TLS.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid TLS minVersion %q", c.TLS.MinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	return tlsConfig, nil
+}
+
+// PublishOptionsFor returns the util.PublishOptions for the given MQTT topic, applying any
+// TopicQoS/TopicRetain override on top of the module-level QoS/Retain defaults.
+func (c *Configure) PublishOptionsFor(topic string) util.PublishOptions {
+	opts := util.PublishOptions{QoS: c.QoS, Retain: c.Retain}
+	if qos, ok := c.TopicQoS[topic]; ok {
+		opts.QoS = qos
+	}
+	if retain, ok := c.TopicRetain[topic]; ok {
+		opts.Retain = retain
+	}
+	return opts
+}
+
+// SubscribeOptionsFor returns the util.SubscribeOptions for the given MQTT topic, applying any
+// TopicQoS override on top of the module-level QoS default.
+func (c *Configure) SubscribeOptionsFor(topic string) util.SubscribeOptions {
+	opts := util.SubscribeOptions{QoS: c.QoS}
+	if qos, ok := c.TopicQoS[topic]; ok {
+		opts.QoS = qos
+	}
+	return opts
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/common/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha1"
+)
+
+// TestPublishOptionsFor checks PublishOptionsFor falls back to the module-level QoS/Retain
+// defaults and applies per-topic overrides when present.
+func TestPublishOptionsFor(t *testing.T) {
+	c := &Configure{
+		EventBus: v1alpha1.EventBus{
+			QoS:         1,
+			Retain:      false,
+			TopicQoS:    map[string]uint8{"device/twin": 2},
+			TopicRetain: map[string]bool{"device/twin": true},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		topic string
+		want  util.PublishOptions
+	}{
+		{
+			name:  "TestPublishOptionsFor: no override falls back to module defaults",
+			topic: "device/event",
+			want:  util.PublishOptions{QoS: 1, Retain: false},
+		},
+		{
+			name:  "TestPublishOptionsFor: topic override wins",
+			topic: "device/twin",
+			want:  util.PublishOptions{QoS: 2, Retain: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.PublishOptionsFor(tt.topic); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSubscribeOptionsFor checks SubscribeOptionsFor falls back to the module-level QoS
+// default and applies a per-topic override when present.
+func TestSubscribeOptionsFor(t *testing.T) {
+	c := &Configure{
+		EventBus: v1alpha1.EventBus{
+			QoS:      0,
+			TopicQoS: map[string]uint8{"device/twin": 2},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		topic string
+		want  util.SubscribeOptions
+	}{
+		{
+			name:  "TestSubscribeOptionsFor: no override falls back to module default",
+			topic: "device/event",
+			want:  util.SubscribeOptions{QoS: 0},
+		},
+		{
+			name:  "TestSubscribeOptionsFor: topic override wins",
+			topic: "device/twin",
+			want:  util.SubscribeOptions{QoS: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.SubscribeOptionsFor(tt.topic); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestTLSConfig checks TLSConfig converts EventBusTLS into a *util.TLSConfig, parses
+// MinVersion, and preserves the historical insecure behavior when TLS is unset or disabled.
+func TestTLSConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     *v1alpha1.EventBusTLS
+		want    *util.TLSConfig
+		wantErr bool
+	}{
+		{
+			name: "TestTLSConfig: nil TLS config keeps the insecure default",
+			tls:  nil,
+			want: nil,
+		},
+		{
+			name: "TestTLSConfig: TLS present but disabled",
+			tls:  &v1alpha1.EventBusTLS{Enable: false, CAFile: "ca.pem"},
+			want: nil,
+		},
+		{
+			name: "TestTLSConfig: enabled with CA and min version",
+			tls: &v1alpha1.EventBusTLS{
+				Enable:     true,
+				CAFile:     "ca.pem",
+				CertFile:   "client.pem",
+				KeyFile:    "client-key.pem",
+				ServerName: "broker.example.com",
+				MinVersion: "TLS1.2",
+			},
+			want: &util.TLSConfig{
+				CAFile:     "ca.pem",
+				CertFile:   "client.pem",
+				KeyFile:    "client-key.pem",
+				ServerName: "broker.example.com",
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+		{
+			name:    "TestTLSConfig: invalid min version",
+			tls:     &v1alpha1.EventBusTLS{Enable: true, MinVersion: "TLS9.9"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Configure{EventBus: v1alpha1.EventBus{TLS: tt.tls}}
+			got, err := c.TLSConfig()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Errorf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// NewDefaultEdgeCoreConfig returns a full EdgeCoreConfig object with default values filled in.
+func NewDefaultEdgeCoreConfig() *EdgeCoreConfig {
+	return &EdgeCoreConfig{
+		Modules: &Modules{
+			EventBus: newDefaultEventBus(),
+		},
+	}
+}
+
+func newDefaultEventBus() *EventBus {
+	return &EventBus{
+		Enable:             true,
+		MqttServerInternal: "tcp://127.0.0.1:1884",
+		MqttServerExternal: "tcp://127.0.0.1:1883",
+		MqttSubClientID:    "edgecore-sub",
+		MqttPubClientID:    "edgecore-pub",
+	}
+}
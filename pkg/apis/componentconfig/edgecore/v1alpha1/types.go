@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// EdgeCoreConfig is the root of EdgeCore's config.
+type EdgeCoreConfig struct {
+	// Modules indicates EdgeCore's sub modules config
+	Modules *Modules `json:"modules,omitempty"`
+}
+
+// Modules indicates the modules config of EdgeCore.
+type Modules struct {
+	// EventBus indicates eventbus module config
+	EventBus *EventBus `json:"eventBus,omitempty"`
+}
+
+// EventBus indicates the event bus module config.
+type EventBus struct {
+	// Enable indicates whether eventBus is enabled, if set to false (for debugging etc.), skip checking other
+	// eventBus configs.
+	// default true
+	Enable bool `json:"enable,omitempty"`
+	// MqttServerInternal indicates internal mqtt broker url
+	// default tcp://127.0.0.1:1884
+	MqttServerInternal string `json:"mqttServerInternal,omitempty"`
+	// MqttServerExternal indicates external mqtt broker url
+	// default tcp://127.0.0.1:1883
+	MqttServerExternal string `json:"mqttServerExternal,omitempty"`
+	// MqttSubClientID indicates mqtt subscribe ClientID
+	// default "edgecore-sub"
+	MqttSubClientID string `json:"mqttSubClientID,omitempty"`
+	// MqttPubClientID indicates mqtt publish ClientID
+	// default "edgecore-pub"
+	MqttPubClientID string `json:"mqttPubClientID,omitempty"`
+	// TLS indicates the TLS/mTLS options used to connect to the mqtt broker.
+	// When nil, eventbus falls back to an insecure connection for backward compatibility.
+	TLS *EventBusTLS `json:"tls,omitempty"`
+	// QoS indicates the default MQTT QoS (0, 1, or 2) used for publishes that have no
+	// topic-specific override in TopicQoS.
+	// default 0
+	QoS uint8 `json:"qos,omitempty"`
+	// Retain indicates whether publishes are marked retained by default when a topic has no
+	// topic-specific override in TopicRetain.
+	// default false
+	Retain bool `json:"retain,omitempty"`
+	// TopicQoS overrides QoS for specific topics, keyed by MQTT topic name.
+	TopicQoS map[string]uint8 `json:"topicQoS,omitempty"`
+	// TopicRetain overrides Retain for specific topics, keyed by MQTT topic name.
+	TopicRetain map[string]bool `json:"topicRetain,omitempty"`
+}
+
+// EventBusTLS indicates the TLS options eventbus uses to dial the mqtt broker.
+type EventBusTLS struct {
+	// Enable indicates whether the TLS options below are applied when dialing the broker; when
+	// false, the insecure TLS settings are kept. Whether TLS is used at all follows the broker URL scheme.
+	// default false
+	Enable bool `json:"enable,omitempty"`
+	// CAFile is the path to the CA certificate used to verify the broker's server certificate
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile is the path to the client certificate used for mTLS, must be set together with KeyFile
+	CertFile string `json:"certFile,omitempty"`
+	// KeyFile is the path to the client private key used for mTLS, must be set together with CertFile
+	KeyFile string `json:"keyFile,omitempty"`
+	// ServerName overrides the server name used to verify the broker's certificate
+	ServerName string `json:"serverName,omitempty"`
+	// MinVersion is the minimum TLS version accepted, e.g. "TLS1.2". default "TLS1.2"
+	MinVersion string `json:"minVersion,omitempty"`
+	// InsecureSkipVerify disables verification of the broker's certificate chain and host name.
+	// default false
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}